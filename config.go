@@ -11,7 +11,9 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/BurntSushi/toml"
 	goyaml "gopkg.in/yaml.v2"
 )
 
@@ -20,11 +22,23 @@ import (
 // Config represents a configuration with convenient access methods.
 type Config struct {
 	Root interface{}
+
+	// mu guards Root: Set holds it for writing, and readers (root,
+	// mergeFrom, Unmarshal) hold it for reading.
+	mu sync.RWMutex
+}
+
+// root reads cfg.Root under the read lock. The returned tree is shared
+// with cfg, not copied.
+func (cfg *Config) root() interface{} {
+	cfg.mu.RLock()
+	defer cfg.mu.RUnlock()
+	return cfg.Root
 }
 
 // Get returns a nested config according to a dotted path.
 func (cfg *Config) Get(path string) (*Config, error) {
-	n, err := Get(cfg.Root, path)
+	n, err := Get(cfg.root(), path)
 	if err != nil {
 		return nil, err
 	}
@@ -34,6 +48,9 @@ func (cfg *Config) Get(path string) (*Config, error) {
 // Set the value in the structure according to a dotted path.
 // objects that do not exists will be created
 func (cfg *Config) Set(path string, value interface{}) (modified map[string]interface{}, added map[string]interface{}, err error) {
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+
 	path = strings.Trim(path, ".")
 	path = strings.Trim(path, " ")
 
@@ -167,7 +184,7 @@ func makeMap() interface{} {
 
 // Bool returns a bool according to a dotted path.
 func (cfg *Config) Bool(path string) (bool, error) {
-	n, err := Get(cfg.Root, path)
+	n, err := Get(cfg.root(), path)
 	if err != nil {
 		return false, err
 	}
@@ -182,7 +199,7 @@ func (cfg *Config) Bool(path string) (bool, error) {
 
 // Float64 returns a float64 according to a dotted path.
 func (cfg *Config) Float64(path string) (float64, error) {
-	n, err := Get(cfg.Root, path)
+	n, err := Get(cfg.root(), path)
 	if err != nil {
 		return 0, err
 	}
@@ -191,6 +208,8 @@ func (cfg *Config) Float64(path string) (float64, error) {
 		return n, nil
 	case int:
 		return float64(n), nil
+	case int64:
+		return float64(n), nil
 	case string:
 		return strconv.ParseFloat(n, 64)
 	}
@@ -199,7 +218,7 @@ func (cfg *Config) Float64(path string) (float64, error) {
 
 // Int returns an int according to a dotted path.
 func (cfg *Config) Int(path string) (int, error) {
-	n, err := Get(cfg.Root, path)
+	n, err := Get(cfg.root(), path)
 	if err != nil {
 		return 0, err
 	}
@@ -214,6 +233,9 @@ func (cfg *Config) Int(path string) (int, error) {
 		}
 	case int:
 		return n, nil
+	case int64:
+		// TOML unmarshals integers into int64.
+		return int(n), nil
 	case string:
 		if v, err := strconv.ParseInt(n, 10, 0); err == nil {
 			return int(v), nil
@@ -226,7 +248,7 @@ func (cfg *Config) Int(path string) (int, error) {
 
 // List returns a []interface{} according to a dotted path.
 func (cfg *Config) List(path string) ([]interface{}, error) {
-	n, err := Get(cfg.Root, path)
+	n, err := Get(cfg.root(), path)
 	if err != nil {
 		return nil, err
 	}
@@ -238,7 +260,7 @@ func (cfg *Config) List(path string) ([]interface{}, error) {
 
 // Map returns a map[string]interface{} according to a dotted path.
 func (cfg *Config) Map(path string) (map[string]interface{}, error) {
-	n, err := Get(cfg.Root, path)
+	n, err := Get(cfg.root(), path)
 	if err != nil {
 		return nil, err
 	}
@@ -250,7 +272,7 @@ func (cfg *Config) Map(path string) (map[string]interface{}, error) {
 
 // String returns a string according to a dotted path.
 func (cfg *Config) String(path string) (string, error) {
-	n, err := Get(cfg.Root, path)
+	n, err := Get(cfg.root(), path)
 	if err != nil {
 		return "", err
 	}
@@ -364,7 +386,18 @@ func normalizeValue(value interface{}) (interface{}, error) {
 			node[key] = item
 		}
 		return node, nil
-	case bool, float64, int, string, nil:
+	case []map[string]interface{}:
+		// TOML decodes arrays of tables this way.
+		node := make([]interface{}, len(value))
+		for key, v := range value {
+			item, err := normalizeValue(v)
+			if err != nil {
+				return nil, fmt.Errorf("Unsupported list item: %#v", v)
+			}
+			node[key] = item
+		}
+		return node, nil
+	case bool, float64, int, int64, string, nil:
 		return value, nil
 	}
 	return nil, fmt.Errorf("Unsupported type: %T", value)
@@ -445,3 +478,42 @@ func RenderYaml(cfg interface{}) (string, error) {
 	}
 	return string(b), nil
 }
+
+// TOML ------------------------------------------------------------------------
+
+// ParseToml reads a TOML configuration from the given string.
+func ParseToml(cfg string) (*Config, error) {
+	return parseToml([]byte(cfg))
+}
+
+// ParseTomlFile reads a TOML configuration from the given filename.
+func ParseTomlFile(filename string) (*Config, error) {
+	cfg, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return parseToml(cfg)
+}
+
+// parseToml performs the real TOML parsing.
+func parseToml(cfg []byte) (*Config, error) {
+	// TOML documents are always tables at the root.
+	var out map[string]interface{}
+	if err := toml.Unmarshal(cfg, &out); err != nil {
+		return nil, err
+	}
+	normalized, err := normalizeValue(out)
+	if err != nil {
+		return nil, err
+	}
+	return &Config{Root: normalized}, nil
+}
+
+// RenderToml renders a TOML configuration.
+func RenderToml(cfg interface{}) (string, error) {
+	var b strings.Builder
+	if err := toml.NewEncoder(&b).Encode(cfg); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2012 The Gorilla Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import "testing"
+
+func TestParseToml(t *testing.T) {
+	cfg, err := ParseToml(`
+[database]
+host = "localhost"
+port = 5432
+tags = ["a", "b"]
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host, err := cfg.String("database.host")
+	if err != nil || host != "localhost" {
+		t.Fatalf("host: got %v, %v", host, err)
+	}
+
+	port, err := cfg.Int("database.port")
+	if err != nil || port != 5432 {
+		t.Fatalf("port: got %v, %v", port, err)
+	}
+
+	tags, err := cfg.List("database.tags")
+	if err != nil || len(tags) != 2 || tags[0] != "a" || tags[1] != "b" {
+		t.Fatalf("tags: got %v, %v", tags, err)
+	}
+}
+
+func TestParseTomlArrayOfTables(t *testing.T) {
+	cfg, err := ParseToml(`
+[[servers]]
+host = "a"
+
+[[servers]]
+host = "b"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	servers, err := cfg.List("servers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(servers) != 2 {
+		t.Fatalf("got %d servers, want 2", len(servers))
+	}
+	first, ok := servers[0].(map[string]interface{})
+	if !ok || first["host"] != "a" {
+		t.Fatalf("got %v", servers[0])
+	}
+}
+
+func TestParseTomlInvalid(t *testing.T) {
+	if _, err := ParseToml("not = valid = toml"); err == nil {
+		t.Fatal("expected error for invalid TOML")
+	}
+}
+
+func TestRenderToml(t *testing.T) {
+	out, err := RenderToml(map[string]interface{}{"host": "localhost", "port": 5432})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := ParseToml(out)
+	if err != nil {
+		t.Fatalf("round-trip parse failed: %v", err)
+	}
+	host, _ := cfg.String("host")
+	if host != "localhost" {
+		t.Fatalf("got %v", host)
+	}
+}
+
+func TestParseTomlFileNotFound(t *testing.T) {
+	if _, err := ParseTomlFile("/nonexistent/path.toml"); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+}
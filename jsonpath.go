@@ -0,0 +1,217 @@
+// Copyright 2012 The Gorilla Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JSONPath -----------------------------------------------------------------
+
+// Query evaluates a JSONPath expression against the config and returns
+// every matching value. The supported subset is: "$" (root), ".field"
+// and "['field']" member access, "[n]" index access, "[*]" wildcard,
+// ".." recursive descent, and simple equality filters of the form
+// "[?(@.field=='value')]" applied to each element of an array.
+func (cfg *Config) Query(path string) ([]interface{}, error) {
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []interface{}{cfg.root()}
+	for _, step := range steps {
+		var next []interface{}
+		for _, m := range matches {
+			next = append(next, step.apply(m)...)
+		}
+		matches = next
+	}
+	return matches, nil
+}
+
+// jsonPathStep is one segment of a parsed JSONPath expression.
+type jsonPathStep struct {
+	kind   string // "field", "wildcard", "index", "recursive", "filter"
+	field  string
+	index  int
+	filter func(interface{}) bool
+}
+
+func (s jsonPathStep) apply(v interface{}) []interface{} {
+	switch s.kind {
+	case "field":
+		if m, ok := v.(map[string]interface{}); ok {
+			if child, ok := m[s.field]; ok {
+				return []interface{}{child}
+			}
+		}
+		return nil
+
+	case "wildcard":
+		switch c := v.(type) {
+		case map[string]interface{}:
+			out := make([]interface{}, 0, len(c))
+			for _, child := range c {
+				out = append(out, child)
+			}
+			return out
+		case []interface{}:
+			return append([]interface{}{}, c...)
+		}
+		return nil
+
+	case "index":
+		if c, ok := v.([]interface{}); ok && s.index >= 0 && s.index < len(c) {
+			return []interface{}{c[s.index]}
+		}
+		return nil
+
+	case "recursive":
+		var out []interface{}
+		var walk func(interface{})
+		walk = func(node interface{}) {
+			out = append(out, node)
+			switch c := node.(type) {
+			case map[string]interface{}:
+				for _, child := range c {
+					walk(child)
+				}
+			case []interface{}:
+				for _, child := range c {
+					walk(child)
+				}
+			}
+		}
+		walk(v)
+		return out
+
+	case "filter":
+		c, ok := v.([]interface{})
+		if !ok {
+			return nil
+		}
+		var out []interface{}
+		for _, item := range c {
+			if s.filter(item) {
+				out = append(out, item)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// parseJSONPath parses the subset of JSONPath described in Query's doc
+// comment into a sequence of steps.
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("Invalid JSONPath %q: must start with \"$\"", path)
+	}
+	rest := path[1:]
+
+	var steps []jsonPathStep
+	for len(rest) > 0 {
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			steps = append(steps, jsonPathStep{kind: "recursive"})
+			rest = rest[2:]
+			// "$..field" omits the "." before the field name; "$..[*]"
+			// and "$...field" (explicit separator) still work as-is.
+			if len(rest) > 0 && rest[0] != '.' && rest[0] != '[' {
+				name, remainder := takeIdentifier(rest)
+				steps = append(steps, jsonPathStep{kind: "field", field: name})
+				rest = remainder
+			}
+
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			name, remainder := takeIdentifier(rest)
+			if name == "" {
+				return nil, fmt.Errorf("Invalid JSONPath %q: expected field name after \".\"", path)
+			}
+			steps = append(steps, jsonPathStep{kind: "field", field: name})
+			rest = remainder
+
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("Invalid JSONPath %q: unterminated \"[\"", path)
+			}
+			inner := rest[1:end]
+			step, err := parseBracketExpr(inner, path)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+			rest = rest[end+1:]
+
+		default:
+			return nil, fmt.Errorf("Invalid JSONPath %q: unexpected character %q", path, rest[:1])
+		}
+	}
+	return steps, nil
+}
+
+// parseBracketExpr parses the contents of a single "[...]" segment.
+func parseBracketExpr(inner, fullPath string) (jsonPathStep, error) {
+	switch {
+	case inner == "*":
+		return jsonPathStep{kind: "wildcard"}, nil
+
+	case strings.HasPrefix(inner, "'") && strings.HasSuffix(inner, "'") && len(inner) >= 2:
+		return jsonPathStep{kind: "field", field: inner[1 : len(inner)-1]}, nil
+
+	case strings.HasPrefix(inner, "?(") && strings.HasSuffix(inner, ")"):
+		filter, err := parseFilterExpr(inner[2 : len(inner)-1])
+		if err != nil {
+			return jsonPathStep{}, fmt.Errorf("Invalid JSONPath %q: %v", fullPath, err)
+		}
+		return jsonPathStep{kind: "filter", filter: filter}, nil
+
+	default:
+		i, err := strconv.Atoi(inner)
+		if err != nil {
+			return jsonPathStep{}, fmt.Errorf("Invalid JSONPath %q: unsupported selector [%s]", fullPath, inner)
+		}
+		return jsonPathStep{kind: "index", index: i}, nil
+	}
+}
+
+// parseFilterExpr parses "@.field=='value'" style equality filters.
+func parseFilterExpr(expr string) (func(interface{}) bool, error) {
+	parts := strings.SplitN(expr, "==", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("unsupported filter expression %q", expr)
+	}
+	field := strings.TrimSpace(parts[0])
+	field = strings.TrimPrefix(field, "@.")
+	want := strings.TrimSpace(parts[1])
+	want = strings.Trim(want, "'\"")
+
+	return func(item interface{}) bool {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		value, ok := m[field]
+		if !ok {
+			return false
+		}
+		return fmt.Sprint(value) == want
+	}, nil
+}
+
+// takeIdentifier consumes a bare identifier (field name) from the start of
+// s, stopping at "." or "[".
+func takeIdentifier(s string) (name, rest string) {
+	i := 0
+	for i < len(s) && s[i] != '.' && s[i] != '[' {
+		i++
+	}
+	return s[:i], s[i:]
+}
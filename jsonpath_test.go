@@ -0,0 +1,89 @@
+// Copyright 2012 The Gorilla Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestQueryWildcard(t *testing.T) {
+	cfg, err := ParseJson(`{"servers":[{"host":"a"},{"host":"b"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := cfg.Query("$.servers[*].host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, []interface{}{"a", "b"}) {
+		t.Fatalf("got %v", got)
+	}
+}
+
+func TestQueryIndex(t *testing.T) {
+	cfg, err := ParseJson(`{"servers":[{"host":"a"},{"host":"b"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := cfg.Query("$.servers[1].host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, []interface{}{"b"}) {
+		t.Fatalf("got %v", got)
+	}
+}
+
+func TestQueryRecursiveDescentField(t *testing.T) {
+	cfg, err := ParseJson(`{
+		"deployments": [
+			{"name": "a", "containers": [{"image": "x:1"}]},
+			{"name": "b", "image": "y:2"}
+		]
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := cfg.Query("$..image")
+	if err != nil {
+		t.Fatal(err)
+	}
+	strs := make([]string, len(got))
+	for i, v := range got {
+		strs[i] = v.(string)
+	}
+	sort.Strings(strs)
+	want := []string{"x:1", "y:2"}
+	if !reflect.DeepEqual(strs, want) {
+		t.Fatalf("got %v, want %v", strs, want)
+	}
+}
+
+func TestQueryFilter(t *testing.T) {
+	cfg, err := ParseJson(`{"servers":[{"name":"a","port":1},{"name":"b","port":2}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := cfg.Query("$.servers[?(@.name=='b')]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %v", got)
+	}
+	m := got[0].(map[string]interface{})
+	if m["name"] != "b" {
+		t.Fatalf("got %v", m)
+	}
+}
+
+func TestQueryInvalidPath(t *testing.T) {
+	cfg, _ := ParseJson(`{}`)
+	if _, err := cfg.Query("servers"); err == nil {
+		t.Fatal("expected error for path not starting with $")
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2012 The Gorilla Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// MergeOptions controls how Merge and MergeWith combine layers.
+type MergeOptions struct {
+	// AppendLists makes lists concatenate (base..., then override...)
+	// instead of the override replacing the base outright.
+	AppendLists bool
+}
+
+// Merge composes several configs into a single Config, applying them in
+// order so that later layers take precedence over earlier ones. Maps are
+// merged key-by-key (recursively); lists are replaced wholesale by the
+// value from the higher layer. It is equivalent to
+// MergeWith(MergeOptions{}, cfgs...).
+//
+// Merge does not modify any of the given configs; it builds a new tree.
+func Merge(cfgs ...*Config) *Config {
+	return MergeWith(MergeOptions{}, cfgs...)
+}
+
+// MergeWith is Merge with control over how lists are combined; see
+// MergeOptions.
+func MergeWith(opts MergeOptions, cfgs ...*Config) *Config {
+	var root interface{}
+	for _, cfg := range cfgs {
+		if cfg == nil {
+			continue
+		}
+		root = mergeFrom(root, cfg, opts)
+	}
+	return &Config{Root: root}
+}
+
+// mergeFrom merges cfg's tree on top of base. It holds cfg's read lock for
+// the whole walk, not just the initial Root read, so a concurrent Set on
+// cfg can't mutate a map mid-iteration.
+func mergeFrom(base interface{}, cfg *Config, opts MergeOptions) interface{} {
+	cfg.mu.RLock()
+	defer cfg.mu.RUnlock()
+	return mergeValue(base, cfg.Root, opts)
+}
+
+// mergeValue merges override on top of base following Merge's precedence
+// rules and returns the resulting value. Every value that ends up in the
+// result is deep-copied, never aliased from base or override, so later
+// mutations (e.g. via Set) on either input can't reach back into the
+// merged tree.
+func mergeValue(base, override interface{}, opts MergeOptions) interface{} {
+	if opts.AppendLists {
+		if baseList, ok := base.([]interface{}); ok {
+			if overrideList, ok := override.([]interface{}); ok {
+				merged := make([]interface{}, 0, len(baseList)+len(overrideList))
+				for _, v := range baseList {
+					merged = append(merged, deepCopy(v))
+				}
+				for _, v := range overrideList {
+					merged = append(merged, deepCopy(v))
+				}
+				return merged
+			}
+		}
+	}
+
+	baseMap, baseIsMap := base.(map[string]interface{})
+	overrideMap, overrideIsMap := override.(map[string]interface{})
+	if !baseIsMap || !overrideIsMap {
+		return deepCopy(override)
+	}
+
+	merged := make(map[string]interface{}, len(baseMap)+len(overrideMap))
+	for k, v := range baseMap {
+		merged[k] = deepCopy(v)
+	}
+	for k, v := range overrideMap {
+		if existing, ok := merged[k]; ok {
+			merged[k] = mergeValue(existing, v, opts)
+		} else {
+			merged[k] = deepCopy(v)
+		}
+	}
+	return merged
+}
+
+// deepCopy recursively clones the maps and slices in v so the result shares
+// no mutable state with v. Scalars are returned as-is since they're
+// immutable.
+func deepCopy(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		cloned := make(map[string]interface{}, len(val))
+		for k, child := range val {
+			cloned[k] = deepCopy(child)
+		}
+		return cloned
+	case []interface{}:
+		cloned := make([]interface{}, len(val))
+		for i, child := range val {
+			cloned[i] = deepCopy(child)
+		}
+		return cloned
+	default:
+		return v
+	}
+}
+
+// LoadEnv builds a Config out of the environment variables that start with
+// prefix followed by an underscore, e.g. with prefix "MYAPP",
+// MYAPP_DATABASE_HOST=localhost becomes the dotted path
+// "database.host" = "localhost". The variable name is lower-cased and its
+// remaining underscores are treated as path separators.
+//
+// LoadEnv is meant to be used as the top layer in a Merge call, so that
+// environment variables override values loaded from a config file:
+//
+//	cfg := config.Merge(fileCfg, config.LoadEnv("MYAPP"))
+func LoadEnv(prefix string) *Config {
+	cfg := &Config{Root: make(map[string]interface{})}
+	prefix = strings.ToUpper(prefix) + "_"
+	for _, kv := range os.Environ() {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[0], prefix) {
+			continue
+		}
+		path := strings.ToLower(strings.TrimPrefix(parts[0], prefix))
+		path = strings.Replace(path, "_", ".", -1)
+		cfg.Set(path, parts[1])
+	}
+	return cfg
+}
@@ -0,0 +1,113 @@
+// Copyright 2012 The Gorilla Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestMergeScalarOverride(t *testing.T) {
+	base, _ := ParseJson(`{"database":{"host":"base","port":1}}`)
+	override, _ := ParseJson(`{"database":{"port":2}}`)
+	merged := Merge(base, override)
+
+	host, _ := merged.String("database.host")
+	port, _ := merged.Int("database.port")
+	if host != "base" || port != 2 {
+		t.Fatalf("got host=%v port=%v", host, port)
+	}
+}
+
+func TestMergeListsReplaceByDefault(t *testing.T) {
+	base, _ := ParseJson(`{"list":[1,2]}`)
+	override, _ := ParseJson(`{"list":[3,4]}`)
+	merged := Merge(base, override)
+
+	list, err := merged.List("list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []interface{}{float64(3), float64(4)}
+	if !reflect.DeepEqual(list, want) {
+		t.Fatalf("got %v, want %v", list, want)
+	}
+}
+
+func TestMergeWithAppendLists(t *testing.T) {
+	base, _ := ParseJson(`{"list":[1,2]}`)
+	override, _ := ParseJson(`{"list":[3,4]}`)
+	merged := MergeWith(MergeOptions{AppendLists: true}, base, override)
+
+	list, err := merged.List("list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []interface{}{float64(1), float64(2), float64(3), float64(4)}
+	if !reflect.DeepEqual(list, want) {
+		t.Fatalf("got %v, want %v", list, want)
+	}
+}
+
+func TestMergeDoesNotAliasInputs(t *testing.T) {
+	base, _ := ParseJson(`{"database":{"host":"a"}}`)
+	override, _ := ParseJson(`{"other":"x"}`)
+	merged := Merge(base, override)
+
+	base.Set("database.host", "MUTATED")
+
+	host, err := merged.String("database.host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host != "a" {
+		t.Fatalf("merged tree was mutated by a later Set on an input: got %v", host)
+	}
+}
+
+func TestMergeDoesNotAliasOverrideOnlyBranches(t *testing.T) {
+	base, _ := ParseJson(`{}`)
+	override, _ := ParseJson(`{"database":{"host":"a"}}`)
+	merged := Merge(base, override)
+
+	override.Set("database.host", "MUTATED")
+
+	host, err := merged.String("database.host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host != "a" {
+		t.Fatalf("merged tree was mutated by a later Set on an input: got %v", host)
+	}
+}
+
+func TestLoadEnv(t *testing.T) {
+	os.Setenv("MYAPP_DATABASE_HOST", "envhost")
+	defer os.Unsetenv("MYAPP_DATABASE_HOST")
+
+	cfg := LoadEnv("MYAPP")
+	host, err := cfg.String("database.host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host != "envhost" {
+		t.Fatalf("got %v", host)
+	}
+}
+
+func TestLoadEnvOverridesFile(t *testing.T) {
+	os.Setenv("MYAPP_DATABASE_HOST", "envhost")
+	defer os.Unsetenv("MYAPP_DATABASE_HOST")
+
+	fileCfg, _ := ParseJson(`{"database":{"host":"filehost","port":1}}`)
+	merged := Merge(fileCfg, LoadEnv("MYAPP"))
+
+	host, _ := merged.String("database.host")
+	port, _ := merged.Int("database.port")
+	if host != "envhost" || port != 1 {
+		t.Fatalf("got host=%v port=%v", host, port)
+	}
+}
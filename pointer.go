@@ -0,0 +1,66 @@
+// Copyright 2012 The Gorilla Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Pointer ----------------------------------------------------------------
+
+// GetPointer returns a nested config according to an RFC 6901 JSON Pointer,
+// e.g. "/servers/0/host". Unlike the dotted paths used by Get, a pointer
+// can address keys that themselves contain dots, using the "~1" ("/") and
+// "~0" ("~") escapes defined by the RFC.
+func (cfg *Config) GetPointer(pointer string) (*Config, error) {
+	n, err := getPointer(cfg.root(), pointer)
+	if err != nil {
+		return nil, err
+	}
+	return &Config{Root: n}, nil
+}
+
+// getPointer resolves pointer against cfg.
+func getPointer(cfg interface{}, pointer string) (interface{}, error) {
+	if pointer == "" {
+		return cfg, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("Invalid JSON Pointer %q: must start with \"/\"", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for pos, token := range tokens {
+		token = unescapePointerToken(token)
+		switch c := cfg.(type) {
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(c) {
+				return nil, fmt.Errorf("Invalid list index at %q", "/"+strings.Join(tokens[:pos+1], "/"))
+			}
+			cfg = c[i]
+		case map[string]interface{}:
+			value, ok := c[token]
+			if !ok {
+				return nil, fmt.Errorf("Nonexistent map key at %q", "/"+strings.Join(tokens[:pos+1], "/"))
+			}
+			cfg = value
+		default:
+			return nil, fmt.Errorf(
+				"Invalid type at %q: expected []interface{} or map[string]interface{}; got %T",
+				"/"+strings.Join(tokens[:pos+1], "/"), cfg)
+		}
+	}
+	return cfg, nil
+}
+
+// unescapePointerToken reverses the "~1" and "~0" escapes from RFC 6901.
+func unescapePointerToken(token string) string {
+	token = strings.Replace(token, "~1", "/", -1)
+	token = strings.Replace(token, "~0", "~", -1)
+	return token
+}
@@ -0,0 +1,49 @@
+// Copyright 2012 The Gorilla Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import "testing"
+
+func TestGetPointer(t *testing.T) {
+	cfg, err := ParseJson(`{"servers":[{"host":"a"},{"host":"b"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := cfg.GetPointer("/servers/1/host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Root != "b" {
+		t.Fatalf("got %v", got.Root)
+	}
+}
+
+func TestGetPointerEscapes(t *testing.T) {
+	cfg, err := ParseJson(`{"a/b": {"c~d": 1}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := cfg.GetPointer("/a~1b/c~0d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Root.(float64) != 1 {
+		t.Fatalf("got %v", got.Root)
+	}
+}
+
+func TestGetPointerMissingKey(t *testing.T) {
+	cfg, _ := ParseJson(`{"a":1}`)
+	if _, err := cfg.GetPointer("/b"); err == nil {
+		t.Fatal("expected error for missing key")
+	}
+}
+
+func TestGetPointerMustStartWithSlash(t *testing.T) {
+	cfg, _ := ParseJson(`{"a":1}`)
+	if _, err := cfg.GetPointer("a"); err == nil {
+		t.Fatal("expected error for pointer not starting with /")
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright 2012 The Gorilla Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Schema -----------------------------------------------------------------
+
+// ValidationError describes a single schema violation, identified by the
+// same dotted path convention used by Get.
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ValidationErrors collects every violation found by Validate.
+type ValidationErrors []*ValidationError
+
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, e := range errs {
+		messages[i] = e.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Validate checks cfg against schema, a JSON Schema document (draft-07
+// subset: type, required, properties, additionalProperties, items, enum,
+// minimum, maximum, minLength, maxLength, pattern and $ref within the same
+// document). It returns nil if cfg conforms, or a ValidationErrors
+// collecting every violation otherwise.
+func (cfg *Config) Validate(schema *Config) error {
+	var errs ValidationErrors
+	v := &validator{root: schema.root()}
+	v.validate(cfg.root(), schema.root(), "", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// ValidateJsonSchema is a convenience wrapper around Validate that parses
+// the schema from raw JSON.
+func (cfg *Config) ValidateJsonSchema(schemaJSON []byte) error {
+	schema, err := parseJson(schemaJSON)
+	if err != nil {
+		return fmt.Errorf("Invalid JSON Schema: %v", err)
+	}
+	return cfg.Validate(schema)
+}
+
+// validator carries the root schema document around so that "$ref" can be
+// resolved against it regardless of how deep the current schema node is.
+type validator struct {
+	root interface{}
+}
+
+func (v *validator) validate(value, schema interface{}, path string, errs *ValidationErrors) {
+	schemaMap, ok := schema.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if ref, ok := schemaMap["$ref"].(string); ok {
+		resolved, err := getPointer(v.root, strings.TrimPrefix(ref, "#"))
+		if err != nil {
+			v.fail(errs, path, fmt.Sprintf("unresolvable $ref %q: %v", ref, err))
+			return
+		}
+		v.validate(value, resolved, path, errs)
+		return
+	}
+
+	if wantType, ok := schemaMap["type"].(string); ok {
+		if !matchesType(value, wantType) {
+			v.fail(errs, path, fmt.Sprintf("expected type %s, got %s", wantType, jsonType(value)))
+			return
+		}
+	}
+
+	if enum, ok := schemaMap["enum"].([]interface{}); ok {
+		if !v.matchesEnum(value, enum) {
+			v.fail(errs, path, fmt.Sprintf("value %v is not one of %v", value, enum))
+		}
+	}
+
+	switch n := value.(type) {
+	case float64:
+		v.validateNumber(n, schemaMap, path, errs)
+	case int:
+		v.validateNumber(float64(n), schemaMap, path, errs)
+	case int64:
+		// TOML unmarshals integers into int64.
+		v.validateNumber(float64(n), schemaMap, path, errs)
+	case string:
+		v.validateString(n, schemaMap, path, errs)
+	case map[string]interface{}:
+		v.validateObject(n, schemaMap, path, errs)
+	case []interface{}:
+		v.validateArray(n, schemaMap, path, errs)
+	}
+}
+
+func (v *validator) matchesEnum(value interface{}, enum []interface{}) bool {
+	for _, candidate := range enum {
+		if fmt.Sprint(candidate) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *validator) validateNumber(n float64, schemaMap map[string]interface{}, path string, errs *ValidationErrors) {
+	if min, ok := schemaMap["minimum"]; ok {
+		if minF, ok := toFloat(min); ok && n < minF {
+			v.fail(errs, path, fmt.Sprintf("%v is less than minimum %v", n, minF))
+		}
+	}
+	if max, ok := schemaMap["maximum"]; ok {
+		if maxF, ok := toFloat(max); ok && n > maxF {
+			v.fail(errs, path, fmt.Sprintf("%v is greater than maximum %v", n, maxF))
+		}
+	}
+}
+
+func (v *validator) validateString(s string, schemaMap map[string]interface{}, path string, errs *ValidationErrors) {
+	if minLen, ok := schemaMap["minLength"]; ok {
+		if minF, ok := toFloat(minLen); ok && float64(len(s)) < minF {
+			v.fail(errs, path, fmt.Sprintf("length %d is less than minLength %v", len(s), minF))
+		}
+	}
+	if maxLen, ok := schemaMap["maxLength"]; ok {
+		if maxF, ok := toFloat(maxLen); ok && float64(len(s)) > maxF {
+			v.fail(errs, path, fmt.Sprintf("length %d is greater than maxLength %v", len(s), maxF))
+		}
+	}
+	if pattern, ok := schemaMap["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			v.fail(errs, path, fmt.Sprintf("invalid pattern %q in schema: %v", pattern, err))
+		} else if !re.MatchString(s) {
+			v.fail(errs, path, fmt.Sprintf("value %q does not match pattern %q", s, pattern))
+		}
+	}
+}
+
+func (v *validator) validateObject(obj map[string]interface{}, schemaMap map[string]interface{}, path string, errs *ValidationErrors) {
+	if required, ok := schemaMap["required"].([]interface{}); ok {
+		for _, r := range required {
+			name, ok := r.(string)
+			if !ok {
+				continue
+			}
+			if _, present := obj[name]; !present {
+				v.fail(errs, joinPath(path, name), "required field is missing")
+			}
+		}
+	}
+
+	properties, _ := schemaMap["properties"].(map[string]interface{})
+	for key, child := range obj {
+		if propSchema, ok := properties[key]; ok {
+			v.validate(child, propSchema, joinPath(path, key), errs)
+			continue
+		}
+		if additional, ok := schemaMap["additionalProperties"]; ok {
+			if allowed, isBool := additional.(bool); isBool {
+				if !allowed {
+					v.fail(errs, joinPath(path, key), "additional property is not allowed")
+				}
+				continue
+			}
+			v.validate(child, additional, joinPath(path, key), errs)
+		}
+	}
+}
+
+func (v *validator) validateArray(items []interface{}, schemaMap map[string]interface{}, path string, errs *ValidationErrors) {
+	itemSchema, ok := schemaMap["items"]
+	if !ok {
+		return
+	}
+	for i, item := range items {
+		v.validate(item, itemSchema, fmt.Sprintf("%s.%d", path, i), errs)
+	}
+}
+
+func (v *validator) fail(errs *ValidationErrors, path, message string) {
+	*errs = append(*errs, &ValidationError{Path: path, Message: message})
+}
+
+// matchesType reports whether value satisfies the JSON Schema primitive
+// type name wantType.
+func matchesType(value interface{}, wantType string) bool {
+	switch wantType {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	case "number":
+		_, isFloat := value.(float64)
+		_, isInt := value.(int)
+		_, isInt64 := value.(int64)
+		return isFloat || isInt || isInt64
+	case "integer":
+		switch n := value.(type) {
+		case int, int64:
+			return true
+		case float64:
+			return n == float64(int(n))
+		}
+		return false
+	}
+	return true
+}
+
+// jsonType returns the JSON Schema type name for a normalized value, for
+// use in error messages.
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64, int, int64:
+		return "number"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
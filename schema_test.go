@@ -0,0 +1,73 @@
+// Copyright 2012 The Gorilla Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import "testing"
+
+const portSchema = `{"type":"integer","minimum":1,"maximum":65535}`
+
+func TestValidateOk(t *testing.T) {
+	cfg, _ := ParseJson(`{"database":{"host":"localhost","port":5432}}`)
+	schema, _ := ParseJson(`{
+		"type": "object",
+		"required": ["database"],
+		"properties": {
+			"database": {
+				"type": "object",
+				"required": ["host", "port"],
+				"properties": {
+					"host": {"type": "string", "minLength": 1},
+					"port": {"type": "integer", "minimum": 1, "maximum": 65535}
+				}
+			}
+		}
+	}`)
+	if err := cfg.Validate(schema); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+}
+
+func TestValidateRequiredMissing(t *testing.T) {
+	cfg, _ := ParseJson(`{}`)
+	schema, _ := ParseJson(`{"type":"object","required":["host"]}`)
+	if err := cfg.Validate(schema); err == nil {
+		t.Fatal("expected error for missing required field")
+	}
+}
+
+func TestValidateTomlIntegers(t *testing.T) {
+	// The TOML parser normalizes integers to int64; schema validation
+	// must treat those the same as JSON's float64/int.
+	cfg, err := ParseToml("port = 8080\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, _ := ParseJson(`{"type":"object","properties":{"port":` + portSchema + `}}`)
+	if err := cfg.Validate(schema); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+}
+
+func TestValidateTomlIntegerOutOfRange(t *testing.T) {
+	cfg, err := ParseToml("port = 99999\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, _ := ParseJson(`{"type":"object","properties":{"port":` + portSchema + `}}`)
+	if err := cfg.Validate(schema); err == nil {
+		t.Fatal("expected error for out-of-range port")
+	}
+}
+
+func TestValidateJsonSchema(t *testing.T) {
+	cfg, _ := ParseJson(`{"name":""}`)
+	err := cfg.ValidateJsonSchema([]byte(`{
+		"type": "object",
+		"properties": {"name": {"type": "string", "minLength": 1}}
+	}`))
+	if err == nil {
+		t.Fatal("expected error for empty name")
+	}
+}
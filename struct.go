@@ -0,0 +1,293 @@
+// Copyright 2012 The Gorilla Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Unmarshal walks the config tree and populates the fields of v, which must
+// be a pointer to a struct. Fields are matched by the key found in their
+// `config` struct tag; if that tag is absent, the `json` tag is used, then
+// the `yaml` tag, and finally the field name lower-cased. A tag of "-"
+// skips the field. The key is looked up as a single key of the enclosing
+// map: a dot in it is not a path separator, so nested values are reached
+// through nested structs.
+//
+// Nested structs, slices, maps with string keys, pointer fields and embedded
+// structs are all supported. Pointer fields are left nil when the
+// corresponding path is absent from the config.
+func (cfg *Config) Unmarshal(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("Unmarshal target must be a non-nil pointer")
+	}
+	// Hold the lock for the whole walk, not just the initial Root read,
+	// so a concurrent Set can't mutate a map mid-traversal.
+	cfg.mu.RLock()
+	defer cfg.mu.RUnlock()
+	return decodeValue("", cfg.Root, rv.Elem())
+}
+
+// Marshal produces a Config from v, which must be a struct or a pointer to
+// one, using the same tag rules as Unmarshal.
+func Marshal(v interface{}) (*Config, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return &Config{Root: nil}, nil
+		}
+		rv = rv.Elem()
+	}
+	root, err := encodeValue(rv)
+	if err != nil {
+		return nil, err
+	}
+	return &Config{Root: root}, nil
+}
+
+// fieldPath returns the map key a struct field is addressed by, and
+// whether the field should be skipped entirely.
+func fieldPath(f reflect.StructField) (path string, skip bool) {
+	for _, tag := range []string{"config", "json", "yaml"} {
+		if raw, ok := f.Tag.Lookup(tag); ok {
+			name := strings.Split(raw, ",")[0]
+			if name == "-" {
+				return "", true
+			}
+			if name != "" {
+				return name, false
+			}
+			break
+		}
+	}
+	return strings.ToLower(f.Name), false
+}
+
+// joinPath joins a parent dotted path with a child segment.
+func joinPath(parent, child string) string {
+	if parent == "" {
+		return child
+	}
+	return parent + "." + child
+}
+
+// decodeValue populates dst (addressable) from src, which comes from the
+// normalized config tree. path is the dotted path of dst, used for errors.
+func decodeValue(path string, src interface{}, dst reflect.Value) error {
+	if src == nil {
+		return nil
+	}
+
+	if dst.Kind() == reflect.Ptr {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return decodeValue(path, src, dst.Elem())
+	}
+
+	switch dst.Kind() {
+	case reflect.Struct:
+		srcMap, ok := src.(map[string]interface{})
+		if !ok {
+			return fieldTypeMismatch(path, "map[string]interface{}", src)
+		}
+		t := dst.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" && !f.Anonymous {
+				continue // unexported
+			}
+			if f.Anonymous {
+				if err := decodeValue(path, src, dst.Field(i)); err != nil {
+					return err
+				}
+				continue
+			}
+			name, skip := fieldPath(f)
+			if skip {
+				continue
+			}
+			child, ok := srcMap[name]
+			if !ok {
+				continue
+			}
+			if err := decodeValue(joinPath(path, name), child, dst.Field(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Map:
+		srcMap, ok := src.(map[string]interface{})
+		if !ok {
+			return fieldTypeMismatch(path, "map[string]interface{}", src)
+		}
+		m := reflect.MakeMapWithSize(dst.Type(), len(srcMap))
+		elemType := dst.Type().Elem()
+		for k, v := range srcMap {
+			elem := reflect.New(elemType).Elem()
+			if err := decodeValue(joinPath(path, k), v, elem); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(k), elem)
+		}
+		dst.Set(m)
+		return nil
+
+	case reflect.Slice:
+		srcList, ok := src.([]interface{})
+		if !ok {
+			return fieldTypeMismatch(path, "[]interface{}", src)
+		}
+		s := reflect.MakeSlice(dst.Type(), len(srcList), len(srcList))
+		for i, v := range srcList {
+			if err := decodeValue(fmt.Sprintf("%s.%d", path, i), v, s.Index(i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(s)
+		return nil
+
+	case reflect.String:
+		s, ok := src.(string)
+		if !ok {
+			return fieldTypeMismatch(path, "string", src)
+		}
+		dst.SetString(s)
+		return nil
+
+	case reflect.Bool:
+		b, ok := src.(bool)
+		if !ok {
+			return fieldTypeMismatch(path, "bool", src)
+		}
+		dst.SetBool(b)
+		return nil
+
+	case reflect.Float32, reflect.Float64:
+		switch n := src.(type) {
+		case float64:
+			dst.SetFloat(n)
+			return nil
+		case int:
+			dst.SetFloat(float64(n))
+			return nil
+		}
+		return fieldTypeMismatch(path, "float64", src)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch n := src.(type) {
+		case float64:
+			dst.SetInt(int64(n))
+			return nil
+		case int:
+			dst.SetInt(int64(n))
+			return nil
+		case int64:
+			dst.SetInt(n)
+			return nil
+		}
+		return fieldTypeMismatch(path, "int", src)
+
+	case reflect.Interface:
+		dst.Set(reflect.ValueOf(src))
+		return nil
+	}
+
+	return fmt.Errorf("field %s: unsupported type %s", path, dst.Type())
+}
+
+// encodeValue turns a Go value back into the normalized config tree.
+func encodeValue(v reflect.Value) (interface{}, error) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		node := make(map[string]interface{})
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" && !f.Anonymous {
+				continue
+			}
+			if f.Anonymous {
+				embedded, err := encodeValue(v.Field(i))
+				if err != nil {
+					return nil, err
+				}
+				if embeddedMap, ok := embedded.(map[string]interface{}); ok {
+					for k, val := range embeddedMap {
+						node[k] = val
+					}
+				}
+				continue
+			}
+			name, skip := fieldPath(f)
+			if skip {
+				continue
+			}
+			value, err := encodeValue(v.Field(i))
+			if err != nil {
+				return nil, err
+			}
+			node[name] = value
+		}
+		return node, nil
+
+	case reflect.Map:
+		node := make(map[string]interface{}, v.Len())
+		for _, key := range v.MapKeys() {
+			value, err := encodeValue(v.MapIndex(key))
+			if err != nil {
+				return nil, err
+			}
+			node[fmt.Sprint(key.Interface())] = value
+		}
+		return node, nil
+
+	case reflect.Slice, reflect.Array:
+		node := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			value, err := encodeValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			node[i] = value
+		}
+		return node, nil
+
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int()), nil
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return encodeValue(v.Elem())
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", v.Type())
+}
+
+// fieldTypeMismatch returns a path-qualified type mismatch error.
+func fieldTypeMismatch(path, expected string, got interface{}) error {
+	if path == "" {
+		return typeMismatch(expected, got)
+	}
+	return fmt.Errorf("field %s: expected %s, got %T", path, expected, got)
+}
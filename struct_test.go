@@ -0,0 +1,212 @@
+// Copyright 2012 The Gorilla Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type databaseConfig struct {
+	Host string `config:"host"`
+	Port int    `config:"port"`
+}
+
+type common struct {
+	Env string `config:"env"`
+}
+
+type appConfig struct {
+	common
+	Database databaseConfig    `config:"database"`
+	Tags     []string          `config:"tags"`
+	Limits   map[string]int    `config:"limits"`
+	Labels   map[string]string `config:"labels"`
+	Timeout  *int              `config:"timeout"`
+}
+
+func TestUnmarshalNestedStruct(t *testing.T) {
+	cfg, err := ParseJson(`{"database":{"host":"localhost","port":5432}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var app appConfig
+	if err := cfg.Unmarshal(&app); err != nil {
+		t.Fatal(err)
+	}
+	if app.Database.Host != "localhost" || app.Database.Port != 5432 {
+		t.Fatalf("got %+v", app.Database)
+	}
+}
+
+func TestUnmarshalEmbeddedStruct(t *testing.T) {
+	cfg, err := ParseJson(`{"env":"production"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var app appConfig
+	if err := cfg.Unmarshal(&app); err != nil {
+		t.Fatal(err)
+	}
+	if app.Env != "production" {
+		t.Fatalf("got %q", app.Env)
+	}
+}
+
+func TestUnmarshalSliceAndMaps(t *testing.T) {
+	cfg, err := ParseJson(`{
+		"tags": ["a", "b"],
+		"limits": {"cpu": 2, "mem": 4},
+		"labels": {"team": "infra"}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var app appConfig
+	if err := cfg.Unmarshal(&app); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(app.Tags, []string{"a", "b"}) {
+		t.Fatalf("tags: got %v", app.Tags)
+	}
+	if !reflect.DeepEqual(app.Limits, map[string]int{"cpu": 2, "mem": 4}) {
+		t.Fatalf("limits: got %v", app.Limits)
+	}
+	if !reflect.DeepEqual(app.Labels, map[string]string{"team": "infra"}) {
+		t.Fatalf("labels: got %v", app.Labels)
+	}
+}
+
+func TestUnmarshalPointerNilIfAbsent(t *testing.T) {
+	cfg, err := ParseJson(`{}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var app appConfig
+	if err := cfg.Unmarshal(&app); err != nil {
+		t.Fatal(err)
+	}
+	if app.Timeout != nil {
+		t.Fatalf("expected nil Timeout, got %v", *app.Timeout)
+	}
+}
+
+func TestUnmarshalPointerSetWhenPresent(t *testing.T) {
+	cfg, err := ParseJson(`{"timeout": 30}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var app appConfig
+	if err := cfg.Unmarshal(&app); err != nil {
+		t.Fatal(err)
+	}
+	if app.Timeout == nil || *app.Timeout != 30 {
+		t.Fatalf("got %v", app.Timeout)
+	}
+}
+
+func TestUnmarshalPathQualifiedError(t *testing.T) {
+	cfg, err := ParseJson(`{"database":{"host":"localhost","port":"not-a-number"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var app appConfig
+	err = cfg.Unmarshal(&app)
+	if err == nil {
+		t.Fatal("expected a type mismatch error")
+	}
+	want := "field database.port: expected int, got string"
+	if err.Error() != want {
+		t.Fatalf("got %q, want %q", err.Error(), want)
+	}
+}
+
+func TestUnmarshalRejectsNonPointer(t *testing.T) {
+	cfg, _ := ParseJson(`{}`)
+	var app appConfig
+	if err := cfg.Unmarshal(app); err == nil {
+		t.Fatal("expected error for non-pointer target")
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	timeout := 42
+	app := appConfig{
+		common:   common{Env: "staging"},
+		Database: databaseConfig{Host: "h", Port: 9},
+		Tags:     []string{"x", "y"},
+		Limits:   map[string]int{"cpu": 1},
+		Labels:   map[string]string{"team": "infra"},
+		Timeout:  &timeout,
+	}
+
+	cfg, err := Marshal(&app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out appConfig
+	if err := cfg.Unmarshal(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(app, out) {
+		t.Fatalf("got %+v, want %+v", out, app)
+	}
+}
+
+func TestMarshalNilPointer(t *testing.T) {
+	var app *appConfig
+	cfg, err := Marshal(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Root != nil {
+		t.Fatalf("got %v", cfg.Root)
+	}
+}
+
+func TestFieldPathFallsBackToJsonTag(t *testing.T) {
+	type T struct {
+		Name string `json:"full_name"`
+	}
+	cfg, err := ParseJson(`{"full_name":"ok"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v T
+	if err := cfg.Unmarshal(&v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Name != "ok" {
+		t.Fatalf("got %q", v.Name)
+	}
+}
+
+func TestFieldPathDashSkipsField(t *testing.T) {
+	type T struct {
+		Secret string `config:"-"`
+	}
+	cfg, err := ParseJson(`{"secret":"shouldnotbind"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v T
+	if err := cfg.Unmarshal(&v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Secret != "" {
+		t.Fatalf("expected skipped field to stay empty, got %q", v.Secret)
+	}
+}
+
+func TestUnmarshalErrorMentionsExpectedField(t *testing.T) {
+	cfg, _ := ParseJson(`{"database":{"host":1}}`)
+	var app appConfig
+	err := cfg.Unmarshal(&app)
+	if err == nil || !strings.Contains(err.Error(), "database.host") {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2012 The Gorilla Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// debounceInterval absorbs the burst of events many editors generate for a
+// single logical save (write-then-rename, multiple writes, etc).
+const debounceInterval = 100 * time.Millisecond
+
+// Diff describes what changed between two successive loads of a watched
+// config file. Each map is keyed by the same dotted path convention used by
+// Get and Set.
+type Diff struct {
+	Added    map[string]interface{}
+	Modified map[string]interface{}
+	Removed  map[string]interface{}
+}
+
+// Watcher watches a config file for changes and keeps the most recently
+// parsed Config available through Current.
+type Watcher struct {
+	filename string
+	watcher  *fsnotify.Watcher
+	done     chan struct{}
+
+	mu      sync.Mutex
+	current *Config
+	timer   *time.Timer
+}
+
+// Watch parses filename (format inferred from its extension: .json, .yaml,
+// .yml or .toml) and keeps reparsing it whenever it changes on disk. On
+// every change, onChange is called with the previous config, the newly
+// loaded config, and a Diff of the dotted paths that were added, modified
+// or removed. If the changed file fails to parse, onChange is not called
+// and Current keeps returning the previous config.
+//
+// The returned *Watcher's Current method always returns the most recently
+// loaded Config, so a caller that only needs the live config (rather than
+// the change notifications) doesn't have to synchronize onChange itself.
+// Close should always be called once the caller is done.
+func Watch(filename string, onChange func(old, new *Config, diff Diff)) (*Watcher, error) {
+	cfg, err := parseFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(filepath.Dir(filename)); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	w := &Watcher{
+		filename: filename,
+		current:  cfg,
+		watcher:  watcher,
+		done:     make(chan struct{}),
+	}
+	go w.run(onChange)
+	return w, nil
+}
+
+// Current returns the most recently loaded Config.
+func (w *Watcher) Current() *Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current
+}
+
+// Close stops watching the file. Any debounce timer that is still pending
+// is stopped, and a reload that starts after Close does nothing. Close does
+// not wait for a reload that is already in progress; that reload may still
+// call onChange after Close returns.
+func (w *Watcher) Close() error {
+	close(w.done)
+	w.mu.Lock()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.mu.Unlock()
+	return w.watcher.Close()
+}
+
+func (w *Watcher) run(onChange func(old, new *Config, diff Diff)) {
+	for {
+		select {
+		case <-w.done:
+			return
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != filepath.Clean(w.filename) {
+				continue
+			}
+			w.mu.Lock()
+			if w.timer == nil {
+				w.timer = time.AfterFunc(debounceInterval, func() { w.reload(onChange) })
+			} else {
+				w.timer.Reset(debounceInterval)
+			}
+			w.mu.Unlock()
+		case <-w.watcher.Errors:
+			// Nothing sensible to do with a watcher-level error other
+			// than keep going; the next successful event will still
+			// trigger a reload.
+		}
+	}
+}
+
+func (w *Watcher) reload(onChange func(old, new *Config, diff Diff)) {
+	select {
+	case <-w.done:
+		return
+	default:
+	}
+
+	next, err := parseFile(w.filename)
+	if err != nil {
+		return
+	}
+
+	w.mu.Lock()
+	old := w.current
+	w.current = next
+	w.mu.Unlock()
+
+	diff := diffConfigs(old.root(), next.root())
+	onChange(old, next, diff)
+}
+
+// parseFile parses filename using the parser selected by its extension.
+func parseFile(filename string) (*Config, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		return ParseJsonFile(filename)
+	case ".yaml", ".yml":
+		return ParseYamlFile(filename)
+	case ".toml":
+		return ParseTomlFile(filename)
+	}
+	return nil, fmt.Errorf("Watch: unrecognized config extension %q", filename)
+}
+
+// diffConfigs walks two normalized trees and reports, by dotted path, which
+// leaves were added, changed or removed going from old to new.
+func diffConfigs(old, new interface{}) Diff {
+	diff := Diff{
+		Added:    make(map[string]interface{}),
+		Modified: make(map[string]interface{}),
+		Removed:  make(map[string]interface{}),
+	}
+	walkDiff("", old, new, &diff)
+	return diff
+}
+
+func walkDiff(path string, old, new interface{}, diff *Diff) {
+	oldMap, oldIsMap := old.(map[string]interface{})
+	newMap, newIsMap := new.(map[string]interface{})
+
+	if oldIsMap && newIsMap {
+		for k, v := range newMap {
+			childPath := joinPath(path, k)
+			if oldVal, ok := oldMap[k]; ok {
+				walkDiff(childPath, oldVal, v, diff)
+			} else {
+				diff.Added[childPath] = v
+			}
+		}
+		for k, v := range oldMap {
+			if _, ok := newMap[k]; !ok {
+				diff.Removed[joinPath(path, k)] = v
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(old, new) {
+		diff.Modified[path] = old
+	}
+}
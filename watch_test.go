@@ -0,0 +1,83 @@
+// Copyright 2012 The Gorilla Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchCurrent(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "cfg.json")
+	if err := os.WriteFile(filename, []byte(`{"a":1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	changed := make(chan Diff, 1)
+	w, err := Watch(filename, func(old, new *Config, diff Diff) {
+		changed <- diff
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	if v, _ := w.Current().Int("a"); v != 1 {
+		t.Fatalf("got %v", v)
+	}
+
+	if err := os.WriteFile(filename, []byte(`{"a":2}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-changed:
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for reload")
+	}
+
+	if v, _ := w.Current().Int("a"); v != 2 {
+		t.Fatalf("Current() not updated after reload, got %v", v)
+	}
+}
+
+func TestWatchCloseStopsPendingReload(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "cfg.json")
+	if err := os.WriteFile(filename, []byte(`{"a":1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fired := make(chan struct{}, 1)
+	w, err := Watch(filename, func(old, new *Config, diff Diff) {
+		select {
+		case fired <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filename, []byte(`{"a":2}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Close well before the debounce interval elapses, while the event
+	// is still pending in the debounce timer.
+	time.Sleep(debounceInterval / 2)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-fired:
+		t.Fatal("onChange fired after Close")
+	case <-time.After(debounceInterval * 2):
+	}
+}